@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParamSpec describes how to generate a single bind parameter for an
+// Operation: a random int in [Min, Max), a random hex string of Length
+// characters (an odd Length is rounded down to even), a UUID, or the
+// current timestamp.
+type ParamSpec struct {
+	Type   string `yaml:"type"`
+	Min    int    `yaml:"min,omitempty"`
+	Max    int    `yaml:"max,omitempty"`
+	Length int    `yaml:"length,omitempty"`
+}
+
+// Execution kinds for a configured Operation (see Operation.Kind).
+const (
+	OpKindExec  = "exec"
+	OpKindQuery = "query"
+)
+
+// Operation is a single named query in a workload mix: its SQL template,
+// the generators for its bind parameters, its selection weight relative
+// to the other operations, and an optional pause after each successful
+// execution (a failed execution is not followed by the pause).
+type Operation struct {
+	Name string `yaml:"name"`
+	SQL  string `yaml:"sql"`
+	// Kind selects how SQL is run: OpKindExec (the default) uses
+	// db.ExecContext, for INSERT/UPDATE/DELETE-shaped operations. Set
+	// it to OpKindQuery for SELECT-shaped operations, so executeOperation
+	// uses db.QueryContext and drains the returned rows - otherwise a read
+	// op's recorded latency and error rate don't reflect real row-fetch
+	// cost.
+	Kind      string        `yaml:"kind,omitempty"`
+	Weight    int           `yaml:"weight,omitempty"`
+	ThinkTime time.Duration `yaml:"think_time,omitempty"`
+	Params    []ParamSpec   `yaml:"params,omitempty"`
+}
+
+// WorkloadConfig is the top-level shape of a --config YAML/JSON file: a
+// named mix of operations plus the global settings that control how long
+// and how hard they are run.
+type WorkloadConfig struct {
+	Concurrency int           `yaml:"concurrency,omitempty"`
+	Duration    time.Duration `yaml:"duration,omitempty"`
+	OpCount     int           `yaml:"op_count,omitempty"`
+	RampUp      time.Duration `yaml:"ramp_up,omitempty"`
+	Operations  []Operation   `yaml:"operations"`
+}
+
+// LoadWorkloadConfig reads and parses a workload config file. YAML is the
+// primary format, but since JSON is a subset of YAML, JSON files parse
+// equally well.
+func LoadWorkloadConfig(path string) (*WorkloadConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading workload config %s: %w", path, err)
+	}
+
+	var cfg WorkloadConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing workload config %s: %w", path, err)
+	}
+	if len(cfg.Operations) == 0 {
+		return nil, fmt.Errorf("workload config %s defines no operations", path)
+	}
+	return &cfg, nil
+}
+
+// opWeight returns op's configured weight, defaulting to 1 when unset.
+func opWeight(op Operation) int {
+	if op.Weight <= 0 {
+		return 1
+	}
+	return op.Weight
+}
+
+// totalWeight sums the effective weight of every operation in the mix.
+func (c *WorkloadConfig) totalWeight() int {
+	total := 0
+	for _, op := range c.Operations {
+		total += opWeight(op)
+	}
+	return total
+}
+
+// pickOperation selects an operation at random, proportionally to its weight.
+func (c *WorkloadConfig) pickOperation() Operation {
+	target := rand.Intn(c.totalWeight())
+	for _, op := range c.Operations {
+		target -= opWeight(op)
+		if target < 0 {
+			return op
+		}
+	}
+	return c.Operations[len(c.Operations)-1]
+}
@@ -0,0 +1,105 @@
+package main
+
+import "fmt"
+
+// Driver captures the SQL dialect differences across backends: the
+// table-creation DDL, the single-row insert/read templates (both using
+// the shared one-column "test(data)" schema), and the bind placeholder
+// syntax for a given argument position (1-based). initializeDB,
+// insertQuery/placeholder (insert_modes.go) and performReads all go
+// through driverFor instead of branching on dbType themselves. cleanupDB
+// (main.go) does not: "DROP TABLE IF EXISTS test" is identical across
+// every registered dialect, so it isn't part of this interface.
+type Driver interface {
+	// Dialect is the driver's name: both its database/sql driver name
+	// (the string passed to sql.Open) and its --drivers flag value.
+	Dialect() string
+	InitDDL() string
+	InsertSQL() string
+	ReadSQL() string
+	Placeholder(i int) string
+}
+
+var driverRegistry = map[string]Driver{}
+
+// registerDriver adds d to the registry under d.Dialect(). Each Driver
+// implementation calls this from its own init().
+func registerDriver(d Driver) {
+	driverRegistry[d.Dialect()] = d
+}
+
+// driverFor looks up a registered Driver by name.
+func driverFor(dbType string) (Driver, bool) {
+	d, ok := driverRegistry[dbType]
+	return d, ok
+}
+
+type sqlite3Driver struct{}
+
+func (sqlite3Driver) Dialect() string { return "sqlite3" }
+func (sqlite3Driver) InitDDL() string {
+	return `CREATE TABLE IF NOT EXISTS test (id INTEGER PRIMARY KEY AUTOINCREMENT, data TEXT)`
+}
+func (sqlite3Driver) InsertSQL() string { return "INSERT INTO test (data) VALUES ($1)" }
+func (sqlite3Driver) ReadSQL() string   { return "SELECT * FROM test ORDER BY RANDOM()" }
+func (sqlite3Driver) Placeholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+type mysqlDriver struct{}
+
+func (mysqlDriver) Dialect() string { return "mysql" }
+func (mysqlDriver) InitDDL() string {
+	return `CREATE TABLE IF NOT EXISTS test (id INT AUTO_INCREMENT PRIMARY KEY, data VARCHAR(255))`
+}
+func (mysqlDriver) InsertSQL() string        { return "INSERT INTO test (data) VALUES (?)" }
+func (mysqlDriver) ReadSQL() string          { return "SELECT * FROM test ORDER BY RAND()" }
+func (mysqlDriver) Placeholder(i int) string { return "?" }
+
+type postgresDriver struct{}
+
+func (postgresDriver) Dialect() string { return "postgres" }
+func (postgresDriver) InitDDL() string {
+	return `CREATE TABLE IF NOT EXISTS test (id SERIAL PRIMARY KEY, data TEXT)`
+}
+func (postgresDriver) InsertSQL() string { return "INSERT INTO test (data) VALUES ($1)" }
+func (postgresDriver) ReadSQL() string   { return "SELECT * FROM test ORDER BY RANDOM()" }
+func (postgresDriver) Placeholder(i int) string {
+	return fmt.Sprintf("$%d", i)
+}
+
+// clickhouseDriver targets ClickHouse via github.com/ClickHouse/clickhouse-go/v2,
+// which registers itself under the database/sql driver name "clickhouse".
+// ClickHouse has no auto-increment column, so the test table carries only
+// the shared "data" column, ordered by an empty tuple (no natural key).
+type clickhouseDriver struct{}
+
+func (clickhouseDriver) Dialect() string { return "clickhouse" }
+func (clickhouseDriver) InitDDL() string {
+	return `CREATE TABLE IF NOT EXISTS test (data String) ENGINE = MergeTree() ORDER BY tuple()`
+}
+func (clickhouseDriver) InsertSQL() string        { return "INSERT INTO test (data) VALUES (?)" }
+func (clickhouseDriver) ReadSQL() string          { return "SELECT * FROM test ORDER BY rand()" }
+func (clickhouseDriver) Placeholder(i int) string { return "?" }
+
+// sqlserverDriver targets SQL Server via github.com/microsoft/go-mssqldb,
+// which registers itself under the database/sql driver name "sqlserver".
+type sqlserverDriver struct{}
+
+func (sqlserverDriver) Dialect() string { return "sqlserver" }
+func (sqlserverDriver) InitDDL() string {
+	return `IF NOT EXISTS (SELECT * FROM sysobjects WHERE name='test' AND xtype='U') CREATE TABLE test (id INT IDENTITY(1,1) PRIMARY KEY, data NVARCHAR(255))`
+}
+func (sqlserverDriver) InsertSQL() string { return "INSERT INTO test (data) VALUES (@p1)" }
+func (sqlserverDriver) ReadSQL() string   { return "SELECT * FROM test ORDER BY NEWID()" }
+func (sqlserverDriver) Placeholder(i int) string {
+	return fmt.Sprintf("@p%d", i)
+}
+
+func init() {
+	registerDriver(sqlite3Driver{})
+	registerDriver(mysqlDriver{})
+	registerDriver(postgresDriver{})
+	registerDriver(clickhouseDriver{})
+	registerDriver(sqlserverDriver{})
+}
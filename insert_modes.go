@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// insertQuery returns the single-row insert template for dbType.
+func insertQuery(dbType string) string {
+	drv, ok := driverFor(dbType)
+	if !ok {
+		log.Fatalf("Unsupported database type: %s", dbType)
+	}
+	return drv.InsertSQL()
+}
+
+// placeholder returns the i'th (1-based) bind placeholder for dbType.
+func placeholder(dbType string, i int) string {
+	drv, ok := driverFor(dbType)
+	if !ok {
+		log.Fatalf("Unsupported database type: %s", dbType)
+	}
+	return drv.Placeholder(i)
+}
+
+// batchInsertQuery builds a multi-row "INSERT ... VALUES (...),(...),..."
+// template for n rows.
+func batchInsertQuery(dbType string, n int) string {
+	rows := make([]string, n)
+	for i := 0; i < n; i++ {
+		rows[i] = fmt.Sprintf("(%s)", placeholder(dbType, i+1))
+	}
+	return "INSERT INTO test (data) VALUES " + strings.Join(rows, ",")
+}
+
+// performInsertsExec runs numInserts single-row inserts, each a fresh
+// db.Exec call (the original, ad-hoc execution path).
+func performInsertsExec(ctx context.Context, m *Metrics, rec *Recorder, db *sql.DB, numInserts int, dbType string, opts Options) time.Duration {
+	query := insertQuery(dbType)
+
+	fmt.Printf("[%s] Starting %d inserts...\n", dbType, numInserts)
+	start := time.Now()
+
+	errCount := runWorkerPool(ctx, m, dbType, numInserts, opts, func() error {
+		randomString, err := generateRandomString(64)
+		if err != nil {
+			log.Printf("[%s] Error generating insert payload: %v", dbType, err)
+			return err
+		}
+
+		r := rand.Intn(10)
+		time.Sleep(time.Duration(r) * time.Microsecond)
+
+		queryCtx, cancel := withQueryTimeout(ctx, opts)
+		defer cancel()
+
+		timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(dbType, "insert"))
+		m.inFlightQueries.WithLabelValues(dbType).Inc()
+		opStart := time.Now()
+		_, err = db.ExecContext(queryCtx, query, randomString)
+		elapsed := time.Since(opStart)
+		m.inFlightQueries.WithLabelValues(dbType).Dec()
+		timer.ObserveDuration()
+		recordOutcome(m, rec, dbType, "insert", query, []interface{}{randomString}, elapsed, err, opts)
+		if err != nil {
+			log.Printf("[%s] Error performing insert: %v", dbType, err)
+			return err
+		}
+		m.opsProcessed.WithLabelValues(dbType, "insert").Inc()
+		return nil
+	})
+
+	duration := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(dbType, "insert").Observe(duration.Seconds())
+	fmt.Printf("[%s] Finished inserts in %.4f seconds (%d errors)\n", dbType, duration.Seconds(), errCount)
+	return duration
+}
+
+// performInsertsPrepared runs numInserts inserts using a statement
+// prepared once per worker goroutine and re-executed for every insert,
+// avoiding the per-call parse/plan overhead of performInsertsExec.
+func performInsertsPrepared(ctx context.Context, m *Metrics, rec *Recorder, db *sql.DB, numInserts int, dbType string, opts Options) time.Duration {
+	query := insertQuery(dbType)
+
+	fmt.Printf("[%s] Starting %d prepared inserts...\n", dbType, numInserts)
+	start := time.Now()
+
+	errCount := runWorkerPoolWithSetup(ctx, m, dbType, numInserts, opts, func() (func() error, func()) {
+		stmt, err := db.Prepare(query)
+		if err != nil {
+			log.Printf("[%s] Error preparing insert statement: %v", dbType, err)
+			return func() error { return err }, nil
+		}
+
+		doOne := func() error {
+			randomString, err := generateRandomString(64)
+			if err != nil {
+				return err
+			}
+
+			queryCtx, cancel := withQueryTimeout(ctx, opts)
+			defer cancel()
+
+			timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(dbType, "insert_prepared"))
+			m.inFlightQueries.WithLabelValues(dbType).Inc()
+			opStart := time.Now()
+			_, err = stmt.ExecContext(queryCtx, randomString)
+			elapsed := time.Since(opStart)
+			m.inFlightQueries.WithLabelValues(dbType).Dec()
+			timer.ObserveDuration()
+			recordOutcome(m, rec, dbType, "insert_prepared", query, []interface{}{randomString}, elapsed, err, opts)
+			if err != nil {
+				log.Printf("[%s] Error performing prepared insert: %v", dbType, err)
+				return err
+			}
+			m.opsProcessed.WithLabelValues(dbType, "insert_prepared").Inc()
+			return nil
+		}
+		return doOne, func() { stmt.Close() }
+	})
+
+	duration := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(dbType, "insert_prepared").Observe(duration.Seconds())
+	fmt.Printf("[%s] Finished prepared inserts in %.4f seconds (%d errors)\n", dbType, duration.Seconds(), errCount)
+	return duration
+}
+
+// performInsertsBatch groups numInserts rows into batches of up to
+// opts.BatchSize (default 100) and inserts each batch with a single
+// multi-row "INSERT ... VALUES (...),(...),..." statement.
+func performInsertsBatch(ctx context.Context, m *Metrics, rec *Recorder, db *sql.DB, numInserts int, dbType string, opts Options) time.Duration {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	batches := (numInserts + batchSize - 1) / batchSize
+
+	fmt.Printf("[%s] Starting %d inserts in %d batches of up to %d...\n", dbType, numInserts, batches, batchSize)
+	start := time.Now()
+
+	var remainingRows int64 = int64(numInserts)
+	errCount := runWorkerPool(ctx, m, dbType, batches, opts, func() error {
+		n := int(atomicAddClamped(&remainingRows, batchSize))
+		if n <= 0 {
+			return nil
+		}
+
+		params := make([]interface{}, n)
+		for i := range params {
+			randomString, err := generateRandomString(64)
+			if err != nil {
+				return err
+			}
+			params[i] = randomString
+		}
+
+		batchQuery := batchInsertQuery(dbType, n)
+		queryCtx, cancel := withQueryTimeout(ctx, opts)
+		defer cancel()
+
+		timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(dbType, "insert_batch"))
+		m.inFlightQueries.WithLabelValues(dbType).Inc()
+		opStart := time.Now()
+		_, err := db.ExecContext(queryCtx, batchQuery, params...)
+		elapsed := time.Since(opStart)
+		m.inFlightQueries.WithLabelValues(dbType).Dec()
+		timer.ObserveDuration()
+		recordOutcome(m, rec, dbType, "insert_batch", batchQuery, params, elapsed, err, opts)
+		if err != nil {
+			log.Printf("[%s] Error performing batch insert: %v", dbType, err)
+			return err
+		}
+		m.opsProcessed.WithLabelValues(dbType, "insert_batch").Add(float64(n))
+		return nil
+	})
+
+	duration := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(dbType, "insert_batch").Observe(duration.Seconds())
+	fmt.Printf("[%s] Finished batch inserts in %.4f seconds (%d errors)\n", dbType, duration.Seconds(), errCount)
+	return duration
+}
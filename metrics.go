@@ -0,0 +1,86 @@
+package main
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// Metrics bundles every Prometheus collector a benchmark run reports
+// through. newMetrics(prometheus.DefaultRegisterer) backs the long-running
+// global /metrics endpoint; /probe instead builds one against a fresh
+// prometheus.Registry per request so concurrent probes don't clobber each
+// other's counters.
+type Metrics struct {
+	opsTotalDuration *prometheus.HistogramVec
+	opsDuration      *prometheus.HistogramVec
+	opsProcessed     *prometheus.CounterVec
+	queryErrors      *prometheus.CounterVec
+	inFlightQueries  *prometheus.GaugeVec
+	activeWorkers    *prometheus.GaugeVec
+	slowQueries      *prometheus.CounterVec
+}
+
+// newMetrics creates a full set of collectors and registers them against reg.
+func newMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		opsTotalDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "db_ops_duration_seconds_total",
+				Help:    "Histogram of the duration of database operations",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"db_type", "query_type"},
+		),
+		opsDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "db_ops_duration_seconds",
+				Help:    "Histogram of the duration of single database operations",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"db_type", "query_type"},
+		),
+		opsProcessed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "db_ops_processed_total",
+				Help: "Total number of database operations",
+			},
+			[]string{"db_type", "query_type"},
+		),
+		queryErrors: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "db_query_errors_total",
+				Help: "Total number of database query errors",
+			},
+			[]string{"db_type", "query_type", "error_kind"},
+		),
+		inFlightQueries: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "db_in_flight_queries",
+				Help: "Number of database queries currently executing",
+			},
+			[]string{"db_type"},
+		),
+		activeWorkers: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "db_active_workers",
+				Help: "Number of worker goroutines currently running a benchmark",
+			},
+			[]string{"db_type"},
+		),
+		slowQueries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "db_slow_queries_total",
+				Help: "Total number of operations whose duration met the slow-SQL threshold",
+			},
+			[]string{"db_type", "query_type"},
+		),
+	}
+
+	reg.MustRegister(
+		m.opsTotalDuration,
+		m.opsDuration,
+		m.opsProcessed,
+		m.queryErrors,
+		m.inFlightQueries,
+		m.activeWorkers,
+		m.slowQueries,
+	)
+	return m
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mongoDBType is the db_type label value and --drivers value for the
+// MongoDB backend. It isn't a SQL dialect, so it sits outside the Driver
+// registry in driver.go and is dispatched to directly from main.
+const mongoDBType = "mongodb"
+
+// mongoBenchmark runs the same one-shot insert/read pass as benchmark
+// (main.go), but against a MongoDB collection instead of a *sql.DB,
+// reporting through the same Metrics so db_type="mongodb" shows up
+// alongside the SQL backends on every histogram and counter.
+func mongoBenchmark(ctx context.Context, m *Metrics, uri string, numInserts, numReads int, opts Options) {
+	runAt := time.Now()
+	rec := newRecorder()
+
+	fmt.Printf("[%s] Connecting to database...\n", mongoDBType)
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatalf("[%s] Error connecting to database: %v", mongoDBType, err)
+	}
+	defer client.Disconnect(ctx)
+
+	collection := client.Database("test").Collection("test")
+
+	fmt.Printf("[%s] Performing inserts...\n", mongoDBType)
+	insertTime := mongoPerformInserts(ctx, m, rec, collection, numInserts, opts)
+
+	fmt.Printf("[%s] Performing reads...\n", mongoDBType)
+	readTime := mongoPerformReads(ctx, m, rec, collection, numReads, opts)
+
+	if _, err := collection.DeleteMany(ctx, bson.M{}); err != nil {
+		log.Fatalf("[%s] Error cleaning up database: %v", mongoDBType, err)
+	}
+
+	fmt.Printf("[%s] Total time: %.4fs\n", mongoDBType, insertTime.Seconds()+readTime.Seconds())
+	fmt.Printf("[%s] Inserts time: %.4fs\n", mongoDBType, insertTime.Seconds())
+	fmt.Printf("[%s] Reads time: %.4fs\n", mongoDBType, readTime.Seconds())
+	writeReport(mongoDBType, rec.report(insertTime+readTime), runAt, opts)
+}
+
+func mongoPerformInserts(ctx context.Context, m *Metrics, rec *Recorder, collection *mongo.Collection, numInserts int, opts Options) time.Duration {
+	fmt.Printf("[%s] Starting %d inserts...\n", mongoDBType, numInserts)
+	start := time.Now()
+
+	errCount := runWorkerPool(ctx, m, mongoDBType, numInserts, opts, func() error {
+		randomString, err := generateRandomString(64)
+		if err != nil {
+			return err
+		}
+
+		queryCtx, cancel := withQueryTimeout(ctx, opts)
+		defer cancel()
+
+		timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(mongoDBType, "insert"))
+		m.inFlightQueries.WithLabelValues(mongoDBType).Inc()
+		opStart := time.Now()
+		_, err = collection.InsertOne(queryCtx, bson.M{"data": randomString})
+		elapsed := time.Since(opStart)
+		m.inFlightQueries.WithLabelValues(mongoDBType).Dec()
+		timer.ObserveDuration()
+		recordOutcome(m, rec, mongoDBType, "insert", "db.test.insertOne({data: ?})", []interface{}{randomString}, elapsed, err, opts)
+		if err != nil {
+			log.Printf("[%s] Error performing insert: %v", mongoDBType, err)
+			return err
+		}
+		m.opsProcessed.WithLabelValues(mongoDBType, "insert").Inc()
+		return nil
+	})
+
+	duration := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(mongoDBType, "insert").Observe(duration.Seconds())
+	fmt.Printf("[%s] Finished inserts in %.4f seconds (%d errors)\n", mongoDBType, duration.Seconds(), errCount)
+	return duration
+}
+
+// mongoPerformReads samples a single random document per read, using
+// $sample as Mongo's equivalent of the SQL drivers' ORDER BY RANDOM().
+func mongoPerformReads(ctx context.Context, m *Metrics, rec *Recorder, collection *mongo.Collection, numReads int, opts Options) time.Duration {
+	pipeline := bson.A{bson.M{"$sample": bson.M{"size": 1}}}
+
+	fmt.Printf("[%s] Starting %d reads...\n", mongoDBType, numReads)
+	start := time.Now()
+
+	errCount := runWorkerPool(ctx, m, mongoDBType, numReads, opts, func() error {
+		queryCtx, cancel := withQueryTimeout(ctx, opts)
+		defer cancel()
+
+		timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(mongoDBType, "read"))
+		m.inFlightQueries.WithLabelValues(mongoDBType).Inc()
+		opStart := time.Now()
+		cursor, err := collection.Aggregate(queryCtx, pipeline)
+		elapsed := time.Since(opStart)
+		m.inFlightQueries.WithLabelValues(mongoDBType).Dec()
+		timer.ObserveDuration()
+		if err != nil {
+			recordOutcome(m, rec, mongoDBType, "read", "db.test.aggregate([{$sample: {size: 1}}])", nil, elapsed, err, opts)
+			log.Printf("[%s] Error performing read: %v", mongoDBType, err)
+			return err
+		}
+		cursor.Close(queryCtx)
+		recordOutcome(m, rec, mongoDBType, "read", "db.test.aggregate([{$sample: {size: 1}}])", nil, elapsed, nil, opts)
+		m.opsProcessed.WithLabelValues(mongoDBType, "read").Inc()
+		return nil
+	})
+
+	duration := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(mongoDBType, "read").Observe(duration.Seconds())
+	fmt.Printf("[%s] Finished reads in %.4f seconds (%d errors)\n", mongoDBType, duration.Seconds(), errCount)
+	return duration
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// withQueryTimeout derives a context bounded by opts.QueryTimeout from
+// ctx. When QueryTimeout is unset (<=0), it returns ctx unchanged with a
+// no-op cancel.
+func withQueryTimeout(ctx context.Context, opts Options) (context.Context, context.CancelFunc) {
+	if opts.QueryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, opts.QueryTimeout)
+}
+
+// recordOutcome classifies err against m.queryErrors (using the "timeout"
+// error_kind when it's a context deadline, "error" otherwise), and - on
+// success - logs and counts a slow query when elapsed meets
+// opts.SlowSQLThreshold. It also feeds rec, the current run's local
+// latency recorder for the end-of-run percentile report (see report.go).
+func recordOutcome(m *Metrics, rec *Recorder, dbType, queryType, sqlText string, params []interface{}, elapsed time.Duration, err error, opts Options) {
+	rec.record(queryType, elapsed, err)
+
+	if err != nil {
+		kind := "error"
+		if errors.Is(err, context.DeadlineExceeded) {
+			kind = "timeout"
+		}
+		m.queryErrors.WithLabelValues(dbType, queryType, kind).Inc()
+		return
+	}
+
+	if opts.SlowSQLThreshold > 0 && elapsed >= opts.SlowSQLThreshold {
+		m.slowQueries.WithLabelValues(dbType, queryType).Inc()
+		log.Printf("WARN [%s] slow %s query (%.4fs): sql=%q params=%v", dbType, queryType, elapsed.Seconds(), sqlText, params)
+	}
+}
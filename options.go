@@ -0,0 +1,28 @@
+package main
+
+import "time"
+
+// Execution modes for the legacy fixed insert loop (see performInserts
+// in insert_modes.go).
+const (
+	ModeExec     = "exec"
+	ModePrepared = "prepared"
+	ModeBatch    = "batch"
+)
+
+// Options holds the CLI-configurable concurrency, connection-pool, and
+// execution-mode settings shared by every benchmark run, whether it's
+// driving the legacy fixed insert/read loop or a configured workload mix.
+type Options struct {
+	Workers          int
+	Rate             float64
+	Duration         time.Duration
+	Ops              int
+	MaxOpenConns     int
+	MaxIdleConns     int
+	Mode             string
+	BatchSize        int
+	QueryTimeout     time.Duration
+	SlowSQLThreshold time.Duration
+	ReportOut        string
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// probeHandler implements a Prometheus multi-target-exporter-style
+// /probe endpoint: it runs a one-shot benchmark against the DSN in
+// ?target= using the driver in ?driver= (any name registered in
+// driver.go's Driver registry), then serves that single run's metrics
+// in text exposition format. Each request gets its own prometheus.Registry,
+// so concurrent probes never share (and can't clobber) each other's
+// counters - unlike the global /metrics endpoint, which accumulates
+// across the long-running loop in main. This lets one exporter instance
+// be scraped for many databases via Prometheus relabel rules instead of
+// hardcoding DSNs in main.
+//
+// driver and target come straight from the request, so a bad value or an
+// unreachable target must never take the process down: driver is checked
+// against the registry up front, and benchmark (main.go) reports connect/
+// DDL failures back as an error instead of calling log.Fatalf.
+func probeHandler(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("target")
+	driver := r.URL.Query().Get("driver")
+	if target == "" || driver == "" {
+		http.Error(w, "probe: target and driver query params are required", http.StatusBadRequest)
+		return
+	}
+	if _, ok := driverFor(driver); !ok {
+		http.Error(w, fmt.Sprintf("probe: unknown driver %q", driver), http.StatusBadRequest)
+		return
+	}
+
+	inserts, err := probeIntParam(r, "inserts", 100)
+	if err != nil {
+		http.Error(w, "probe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	reads, err := probeIntParam(r, "reads", 100)
+	if err != nil {
+		http.Error(w, "probe: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reg := prometheus.NewRegistry()
+	m := newMetrics(reg)
+
+	if err := benchmark(r.Context(), m, driver, target, inserts, reads, nil, Options{}); err != nil {
+		http.Error(w, "probe: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
+
+// probeIntParam parses the named query param as an int, returning def
+// when the param is absent.
+func probeIntParam(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	return strconv.Atoi(raw)
+}
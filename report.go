@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// Recorder locally tracks per-query_type latency samples and error counts
+// for a single benchmark run, independent of the cumulative Prometheus
+// histograms in Metrics. It backs the end-of-run percentile report
+// (OpReport / writeReport) that benchmark and mongoBenchmark emit.
+type Recorder struct {
+	mu     sync.Mutex
+	hists  map[string]*hdrhistogram.Histogram
+	errors map[string]int64
+}
+
+// newRecorder returns an empty Recorder for a single run.
+func newRecorder() *Recorder {
+	return &Recorder{
+		hists:  make(map[string]*hdrhistogram.Histogram),
+		errors: make(map[string]int64),
+	}
+}
+
+// record stores a single operation's outcome under queryType: its
+// latency on success, or an incremented error count otherwise.
+func (r *Recorder) record(queryType string, elapsed time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors[queryType]++
+		return
+	}
+
+	h, ok := r.hists[queryType]
+	if !ok {
+		h = hdrhistogram.New(1, (10 * time.Minute).Nanoseconds(), 3)
+		r.hists[queryType] = h
+	}
+	if err := h.RecordValue(elapsed.Nanoseconds()); err != nil {
+		log.Printf("Recorder: dropping %s sample (%s, outside the 10m histogram range): %v", queryType, elapsed, err)
+	}
+}
+
+// OpReport is one query_type's row in the end-of-run summary: operation
+// counts, error counts, latency percentiles in seconds, and throughput
+// in ops/sec over the run's wall-clock duration.
+type OpReport struct {
+	Op         string  `json:"op"`
+	Count      int64   `json:"count"`
+	Errors     int64   `json:"errors"`
+	P50        float64 `json:"p50"`
+	P90        float64 `json:"p90"`
+	P95        float64 `json:"p95"`
+	P99        float64 `json:"p99"`
+	Max        float64 `json:"max"`
+	Throughput float64 `json:"throughput"`
+}
+
+// report summarizes every query_type seen since newRecorder, computing
+// throughput against the run's total wall-clock duration.
+func (r *Recorder) report(totalElapsed time.Duration) []OpReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make(map[string]struct{}, len(r.hists)+len(r.errors))
+	for op := range r.hists {
+		ops[op] = struct{}{}
+	}
+	for op := range r.errors {
+		ops[op] = struct{}{}
+	}
+
+	reports := make([]OpReport, 0, len(ops))
+	for op := range ops {
+		rep := OpReport{Op: op, Errors: r.errors[op]}
+		if h := r.hists[op]; h != nil {
+			rep.Count = h.TotalCount()
+			rep.P50 = secondsAtQuantile(h, 50)
+			rep.P90 = secondsAtQuantile(h, 90)
+			rep.P95 = secondsAtQuantile(h, 95)
+			rep.P99 = secondsAtQuantile(h, 99)
+			rep.Max = time.Duration(h.Max()).Seconds()
+		}
+		if totalElapsed > 0 {
+			rep.Throughput = float64(rep.Count) / totalElapsed.Seconds()
+		}
+		reports = append(reports, rep)
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Op < reports[j].Op })
+	return reports
+}
+
+func secondsAtQuantile(h *hdrhistogram.Histogram, q float64) float64 {
+	return time.Duration(h.ValueAtQuantile(q)).Seconds()
+}
+
+// writeReport prints reports as a single JSON object to stdout, and -
+// when opts.ReportOut is set - appends one CSV row per op to that file,
+// writing a header first if the file doesn't exist yet.
+func writeReport(dbType string, reports []OpReport, runAt time.Time, opts Options) {
+	type runReport struct {
+		Timestamp time.Time  `json:"timestamp"`
+		DBType    string     `json:"db_type"`
+		Ops       []OpReport `json:"ops"`
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(runReport{Timestamp: runAt, DBType: dbType, Ops: reports}); err != nil {
+		log.Printf("Error encoding report: %v", err)
+	}
+
+	if opts.ReportOut == "" {
+		return
+	}
+	if err := appendReportCSV(opts.ReportOut, dbType, reports, runAt); err != nil {
+		log.Printf("Error writing report CSV %s: %v", opts.ReportOut, err)
+	}
+}
+
+// appendReportCSV appends one row per op to path (timestamp, db_type, op,
+// count, errors, p50, p99, throughput), creating the file with a header
+// if it doesn't already exist.
+func appendReportCSV(path, dbType string, reports []OpReport, runAt time.Time) error {
+	_, statErr := os.Stat(path)
+	needsHeader := os.IsNotExist(statErr)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if needsHeader {
+		if err := w.Write([]string{"timestamp", "db_type", "op", "count", "errors", "p50", "p99", "throughput"}); err != nil {
+			return err
+		}
+	}
+	for _, rep := range reports {
+		row := []string{
+			runAt.Format(time.RFC3339),
+			dbType,
+			rep.Op,
+			strconv.FormatInt(rep.Count, 10),
+			strconv.FormatInt(rep.Errors, 10),
+			strconv.FormatFloat(rep.P50, 'f', 6, 64),
+			strconv.FormatFloat(rep.P99, 'f', 6, 64),
+			strconv.FormatFloat(rep.Throughput, 'f', 4, 64),
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
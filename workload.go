@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// generateParam produces a single bind value for the given ParamSpec.
+func generateParam(spec ParamSpec) (interface{}, error) {
+	switch spec.Type {
+	case "int":
+		min, max := spec.Min, spec.Max
+		if max <= min {
+			max = min + 1
+		}
+		return min + rand.Intn(max-min), nil
+	case "string":
+		length := spec.Length
+		if length <= 0 {
+			length = 64
+		}
+		return generateRandomString(length)
+	case "uuid":
+		return uuid.NewString(), nil
+	case "timestamp":
+		return time.Now(), nil
+	default:
+		return nil, fmt.Errorf("unknown param type: %q", spec.Type)
+	}
+}
+
+func generateParams(specs []ParamSpec) ([]interface{}, error) {
+	params := make([]interface{}, len(specs))
+	for i, spec := range specs {
+		v, err := generateParam(spec)
+		if err != nil {
+			return nil, err
+		}
+		params[i] = v
+	}
+	return params, nil
+}
+
+// executeOperation runs a single configured operation against db and
+// records its outcome in the Prometheus metrics, using op.Name as the
+// query_type label. It honors opts.QueryTimeout and opts.SlowSQLThreshold.
+// op.Kind selects db.ExecContext (OpKindExec, the default) or
+// db.QueryContext (OpKindQuery, draining the returned rows) - see
+// Operation.Kind in config.go.
+func executeOperation(ctx context.Context, m *Metrics, rec *Recorder, db *sql.DB, dbType string, op Operation, opts Options) error {
+	params, err := generateParams(op.Params)
+	if err != nil {
+		return err
+	}
+
+	queryCtx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+
+	timer := prometheus.NewTimer(m.opsDuration.WithLabelValues(dbType, op.Name))
+	opStart := time.Now()
+	if op.Kind == OpKindQuery {
+		err = drainQuery(queryCtx, db, op.SQL, params)
+	} else {
+		_, err = db.ExecContext(queryCtx, op.SQL, params...)
+	}
+	elapsed := time.Since(opStart)
+	timer.ObserveDuration()
+	recordOutcome(m, rec, dbType, op.Name, op.SQL, params, elapsed, err, opts)
+	if err != nil {
+		return err
+	}
+
+	m.opsProcessed.WithLabelValues(dbType, op.Name).Inc()
+	if op.ThinkTime > 0 {
+		time.Sleep(op.ThinkTime)
+	}
+	return nil
+}
+
+// drainQuery runs sqlText via QueryContext and fully iterates the
+// returned rows, so a read-shaped Operation's recorded latency includes
+// actual row-fetch cost rather than just statement dispatch.
+func drainQuery(ctx context.Context, db *sql.DB, sqlText string, params []interface{}) error {
+	rows, err := db.QueryContext(ctx, sqlText, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+// effectiveDuration, effectiveOpCount, and effectiveWorkers apply CLI
+// overrides (opts) on top of a workload config's own settings; the CLI
+// flags win when set (i.e. > 0 - each defaults to 0, "unset").
+func effectiveDuration(cfg *WorkloadConfig, opts Options) time.Duration {
+	if opts.Duration > 0 {
+		return opts.Duration
+	}
+	return cfg.Duration
+}
+
+func effectiveOpCount(cfg *WorkloadConfig, opts Options) int {
+	if opts.Ops > 0 {
+		return opts.Ops
+	}
+	return cfg.OpCount
+}
+
+func effectiveWorkers(cfg *WorkloadConfig, opts Options) int {
+	if opts.Workers > 0 {
+		return opts.Workers
+	}
+	if cfg.Concurrency > 0 {
+		return cfg.Concurrency
+	}
+	return 1
+}
+
+// runWorkload executes cfg's operation mix against db across a pool of
+// concurrent workers (see Options.Workers / cfg.Concurrency), optionally
+// rate-limited (Options.Rate) and ramped up gradually (cfg.RampUp), until
+// either the effective op count has completed or the effective duration
+// has elapsed. It returns the total elapsed time.
+func runWorkload(ctx context.Context, m *Metrics, rec *Recorder, db *sql.DB, dbType string, cfg *WorkloadConfig, opts Options) time.Duration {
+	workers := effectiveWorkers(cfg, opts)
+	duration := effectiveDuration(cfg, opts)
+	opCount := effectiveOpCount(cfg, opts)
+	if duration <= 0 && opCount <= 0 {
+		opCount = 1 // nothing bounds the run; default to a single operation
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fmt.Printf("[%s] Starting workload (%d operations defined, %d workers)...\n", dbType, len(cfg.Operations), workers)
+	start := time.Now()
+
+	rampStep := rampUpStep(cfg.RampUp, workers)
+	limiter := newLimiter(opts.Rate)
+
+	var wg sync.WaitGroup
+	var errCount int64
+	var remaining int64
+	if opCount > 0 {
+		remaining = int64(opCount)
+	}
+
+	m.activeWorkers.WithLabelValues(dbType).Add(float64(workers))
+	defer m.activeWorkers.WithLabelValues(dbType).Sub(float64(workers))
+
+	for w := 0; w < workers; w++ {
+		if w > 0 && rampStep > 0 {
+			time.Sleep(rampStep)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if opCount > 0 && atomic.AddInt64(&remaining, -1) < 0 {
+					return
+				}
+				select {
+				case <-runCtx.Done():
+					return
+				default:
+				}
+				if limiter != nil {
+					if err := limiter.Wait(runCtx); err != nil {
+						return
+					}
+				}
+
+				op := cfg.pickOperation()
+				m.inFlightQueries.WithLabelValues(dbType).Inc()
+				err := executeOperation(runCtx, m, rec, db, dbType, op, opts)
+				m.inFlightQueries.WithLabelValues(dbType).Dec()
+				if err != nil {
+					atomic.AddInt64(&errCount, 1)
+					log.Printf("[%s] Error performing %s: %v", dbType, op.Name, err)
+				}
+
+				if opCount == 0 && duration == 0 {
+					return
+				}
+			}
+		}()
+	}
+
+	// The duration bound starts once every worker has been launched, so
+	// ramp-up time isn't stolen from the run itself.
+	if duration > 0 {
+		go func() {
+			timer := time.NewTimer(duration)
+			defer timer.Stop()
+			select {
+			case <-timer.C:
+				cancel()
+			case <-runCtx.Done():
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	m.opsTotalDuration.WithLabelValues(dbType, "workload").Observe(elapsed.Seconds())
+	fmt.Printf("[%s] Finished workload in %.4f seconds (%d errors)\n", dbType, elapsed.Seconds(), errCount)
+	return elapsed
+}
+
+// rampUpStep spreads the start of workers goroutines evenly across
+// rampUp, returning the delay to apply before launching each one.
+func rampUpStep(rampUp time.Duration, workers int) time.Duration {
+	if rampUp <= 0 || workers <= 1 {
+		return 0
+	}
+	return rampUp / time.Duration(workers)
+}